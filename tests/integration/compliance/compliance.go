@@ -0,0 +1,161 @@
+// Package compliance provides post-apply assertion helpers that query AWS
+// directly for the state of resources a Terratest run just created, turning
+// plan/apply smoke tests into real compliance-as-code gates.
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/require"
+)
+
+// CheckResult is the machine-readable outcome of a single compliance check.
+// Tests emit one of these per assertion so CI can aggregate pass/fail across
+// runs without scraping log text.
+type CheckResult struct {
+	Check    string `json:"check"`
+	Resource string `json:"resource"`
+	Passed   bool   `json:"passed"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// report marshals the result to JSON, logs it, and fails the test if the
+// check did not pass.
+func (r CheckResult) report(t *testing.T) {
+	t.Helper()
+
+	out, err := json.Marshal(r)
+	require.NoError(t, err, "marshaling compliance check result")
+	t.Log(string(out))
+
+	require.True(t, r.Passed, "%s failed for %s: %s", r.Check, r.Resource, r.Detail)
+}
+
+func ec2Client(t *testing.T) *ec2.Client {
+	t.Helper()
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	require.NoError(t, err, "loading AWS SDK config")
+
+	return ec2.NewFromConfig(cfg)
+}
+
+// AssertNoPublicIngress fails the test if the given security group allows
+// ingress from 0.0.0.0/0 or ::/0 on any port.
+func AssertNoPublicIngress(t *testing.T, sgID string) {
+	t.Helper()
+
+	client := ec2Client(t)
+	out, err := client.DescribeSecurityGroups(context.Background(), &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []string{sgID},
+	})
+	require.NoError(t, err, "describing security group %s", sgID)
+	require.Len(t, out.SecurityGroups, 1, "expected exactly one security group for %s", sgID)
+
+	result := CheckResult{Check: "no_public_ingress", Resource: sgID, Passed: true}
+	for _, perm := range out.SecurityGroups[0].IpPermissions {
+		for _, ipRange := range perm.IpRanges {
+			if aws.ToString(ipRange.CidrIp) == "0.0.0.0/0" {
+				result.Passed = false
+				result.Detail = "ingress rule allows 0.0.0.0/0"
+			}
+		}
+		for _, ipv6Range := range perm.Ipv6Ranges {
+			if aws.ToString(ipv6Range.CidrIpv6) == "::/0" {
+				result.Passed = false
+				result.Detail = "ingress rule allows ::/0"
+			}
+		}
+	}
+
+	result.report(t)
+}
+
+// AssertEbsEncrypted fails the test if the given EBS volume is not encrypted.
+func AssertEbsEncrypted(t *testing.T, volumeID string) {
+	t.Helper()
+
+	client := ec2Client(t)
+	out, err := client.DescribeVolumes(context.Background(), &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	})
+	require.NoError(t, err, "describing volume %s", volumeID)
+	require.Len(t, out.Volumes, 1, "expected exactly one volume for %s", volumeID)
+
+	encrypted := aws.ToBool(out.Volumes[0].Encrypted)
+	result := CheckResult{
+		Check:    "ebs_encrypted",
+		Resource: volumeID,
+		Passed:   encrypted,
+	}
+	if !encrypted {
+		result.Detail = "volume is not encrypted"
+	}
+
+	result.report(t)
+}
+
+// AssertImdsV2Required fails the test if the given instance does not require
+// IMDSv2 (i.e. HttpTokens is not "required").
+func AssertImdsV2Required(t *testing.T, instanceID string) {
+	t.Helper()
+
+	client := ec2Client(t)
+	out, err := client.DescribeInstances(context.Background(), &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	require.NoError(t, err, "describing instance %s", instanceID)
+	require.Len(t, out.Reservations, 1, "expected exactly one reservation for %s", instanceID)
+	require.Len(t, out.Reservations[0].Instances, 1, "expected exactly one instance for %s", instanceID)
+
+	metadataOptions := out.Reservations[0].Instances[0].MetadataOptions
+	required := metadataOptions != nil && metadataOptions.HttpTokens == types.HttpTokensStateRequired
+	result := CheckResult{
+		Check:    "imdsv2_required",
+		Resource: instanceID,
+		Passed:   required,
+	}
+	if !required {
+		result.Detail = "instance metadata options do not require IMDSv2"
+	}
+
+	result.report(t)
+}
+
+// AssertVpcFlowLogsEnabled fails the test if the given VPC has no active flow
+// logs.
+func AssertVpcFlowLogsEnabled(t *testing.T, vpcID string) {
+	t.Helper()
+
+	client := ec2Client(t)
+	out, err := client.DescribeFlowLogs(context.Background(), &ec2.DescribeFlowLogsInput{
+		Filter: []types.Filter{
+			{Name: aws.String("resource-id"), Values: []string{vpcID}},
+		},
+	})
+	require.NoError(t, err, "describing flow logs for %s", vpcID)
+
+	active := false
+	for _, flowLog := range out.FlowLogs {
+		if aws.ToString(flowLog.FlowLogStatus) == "ACTIVE" {
+			active = true
+		}
+	}
+
+	result := CheckResult{
+		Check:    "vpc_flow_logs_enabled",
+		Resource: vpcID,
+		Passed:   active,
+	}
+	if !result.Passed {
+		result.Detail = "no active flow logs found for VPC"
+	}
+
+	result.report(t)
+}
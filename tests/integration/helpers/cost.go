@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+)
+
+// infracostBreakdown mirrors the subset of `infracost breakdown --format
+// json` output we care about.
+type infracostBreakdown struct {
+	TotalMonthlyCost string `json:"totalMonthlyCost"`
+}
+
+// EnforceCostBudget plans terraformOptions to a plan file, runs `infracost
+// breakdown` against it, and fails the test if the estimated monthly cost
+// exceeds maxUSDPerMonth. This turns a cost assumption into an enforced
+// ceiling instead of a comment at the top of the test file.
+func EnforceCostBudget(t *testing.T, terraformOptions *terraform.Options, maxUSDPerMonth float64) {
+	t.Helper()
+
+	// PlanFilePath, not a raw RunTerraformCommand, is what routes
+	// terraformOptions' Vars/VarFiles/Targets through FormatArgs - without
+	// it the plan ignores name_prefix and friends entirely.
+	terraformOptions.PlanFilePath = filepath.Join(terraformOptions.TerraformDir, "cost-estimate.tfplan")
+	terraform.InitAndPlan(t, terraformOptions)
+
+	out, err := exec.Command("infracost", "breakdown", "--path", terraformOptions.PlanFilePath, "--format", "json").Output()
+	if err != nil {
+		t.Fatalf("running infracost breakdown: %v", err)
+	}
+
+	var breakdown infracostBreakdown
+	if err := json.Unmarshal(out, &breakdown); err != nil {
+		t.Fatalf("parsing infracost output: %v", err)
+	}
+
+	monthlyCost, err := strconv.ParseFloat(breakdown.TotalMonthlyCost, 64)
+	if err != nil {
+		t.Fatalf("parsing totalMonthlyCost %q: %v", breakdown.TotalMonthlyCost, err)
+	}
+
+	t.Logf("estimated monthly cost: $%.2f (budget: $%.2f)", monthlyCost, maxUSDPerMonth)
+	if monthlyCost > maxUSDPerMonth {
+		t.Fatalf("estimated monthly cost $%.2f exceeds budget of $%.2f/mo", monthlyCost, maxUSDPerMonth)
+	}
+}
+
+// planResourceChanges is the subset of `terraform show -json <planfile>`
+// we need to sum EBS volume sizes.
+type planResourceChanges struct {
+	ResourceChanges []struct {
+		Type   string `json:"type"`
+		Change struct {
+			After map[string]interface{} `json:"after"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// EnforceVolumeSizeCap plans terraformOptions and fails the test if the sum
+// of every planned standalone aws_ebs_volume's size plus every aws_instance
+// root_block_device's volume_size exceeds maxTotalGB. volume_size configures
+// the instance's root_block_device, while data_volume_size configures the
+// standalone data volume, so both have to be counted to catch a fat-fingered
+// change to either var before it reaches apply.
+func EnforceVolumeSizeCap(t *testing.T, terraformOptions *terraform.Options, maxTotalGB int) {
+	t.Helper()
+
+	// PlanFilePath, not a raw RunTerraformCommand, is what routes
+	// terraformOptions' Vars/VarFiles/Targets through FormatArgs - without
+	// it the plan ignores volume_size/data_volume_size entirely.
+	terraformOptions.PlanFilePath = filepath.Join(terraformOptions.TerraformDir, "volume-cap.tfplan")
+	terraform.InitAndPlan(t, terraformOptions)
+	showOut := terraform.RunTerraformCommand(t, terraformOptions, "show", "-json", terraformOptions.PlanFilePath)
+
+	var plan planResourceChanges
+	if err := json.Unmarshal([]byte(showOut), &plan); err != nil {
+		t.Fatalf("parsing terraform plan JSON: %v", err)
+	}
+
+	total := 0
+	for _, rc := range plan.ResourceChanges {
+		switch rc.Type {
+		case "aws_ebs_volume":
+			if size, ok := rc.Change.After["size"].(float64); ok {
+				total += int(size)
+			}
+
+		case "aws_instance":
+			rootDevices, ok := rc.Change.After["root_block_device"].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, rd := range rootDevices {
+				rdMap, ok := rd.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if size, ok := rdMap["volume_size"].(float64); ok {
+					total += int(size)
+				}
+			}
+		}
+	}
+
+	t.Logf("planned total EBS volume size: %dGB (cap: %dGB)", total, maxTotalGB)
+	if total > maxTotalGB {
+		t.Fatalf("planned EBS volumes total %dGB, which exceeds the %dGB cap", total, maxTotalGB)
+	}
+}
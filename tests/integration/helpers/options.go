@@ -0,0 +1,164 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	testStructure "github.com/gruntwork-io/terratest/modules/test-structure"
+)
+
+// baseVarFile is applied to every test so the suite keeps its shared
+// defaults (instance type, AMI, etc.) in one place.
+const baseVarFile = "tests/integration/test.tfvars"
+
+// perTestVarFileDir holds optional per-test overrides, loaded by test name
+// if present: tests/variables/<TestName>.tfvars.
+const perTestVarFileDir = "tests/variables"
+
+const suffixCharset = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// randomSuffix returns a random lowercase-alphanumeric string of length n,
+// used to keep resource names unique across concurrent test runs.
+func randomSuffix(t *testing.T, n int) string {
+	t.Helper()
+
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		t.Fatalf("generating random suffix: %v", err)
+	}
+
+	out := make([]byte, n)
+	for i, b := range buf {
+		out[i] = suffixCharset[int(b)%len(suffixCharset)]
+	}
+
+	return string(out)
+}
+
+// testEnvDataKey is the SaveTestData/LoadTestData key DefaultOptions uses to
+// persist the temp module dir and name prefix it generates for a test.
+const testEnvDataKey = "test_env"
+
+// testEnv is the part of DefaultOptions' output that must survive across
+// separate `go test` invocations of the same test: once setup has applied
+// real infra into tempModuleDir under namePrefix, a later verify or
+// teardown run (e.g. SKIP_setup=true) has to land on that same dir and
+// prefix rather than generating a fresh, never-applied one.
+type testEnv struct {
+	TempModuleDir string `json:"temp_module_dir"`
+	NamePrefix    string `json:"name_prefix"`
+}
+
+// loadOrCreateTestEnv returns the persisted testEnv for testName if one
+// exists (from an earlier stage in this or a prior invocation), otherwise
+// it creates one - copying the module to a fresh temp dir and generating a
+// new name prefix - and persists it for subsequent invocations to reuse.
+func loadOrCreateTestEnv(t *testing.T, testName string) testEnv {
+	t.Helper()
+
+	var env testEnv
+	if _, err := os.Stat(filepath.Join(dataDir(testName), testEnvDataKey+".json")); err == nil {
+		LoadTestData(t, testName, testEnvDataKey, &env)
+		return env
+	}
+
+	env = testEnv{
+		TempModuleDir: testStructure.CopyTerraformFolderToTemp(t, "../../", "."),
+		NamePrefix:    fmt.Sprintf("devbox-%s-%s", testName, randomSuffix(t, 6)),
+	}
+	SaveTestData(t, testName, testEnvDataKey, env)
+
+	return env
+}
+
+// varFilesFor returns baseVarFile plus tests/variables/<testName>.tfvars, if
+// that per-test override file exists under moduleDir.
+func varFilesFor(moduleDir, testName string) []string {
+	varFiles := []string{baseVarFile}
+
+	perTestVarFile := filepath.Join(perTestVarFileDir, testName+".tfvars")
+	if _, err := os.Stat(filepath.Join(moduleDir, perTestVarFile)); err == nil {
+		varFiles = append(varFiles, perTestVarFile)
+	}
+
+	return varFiles
+}
+
+// StagedOptions builds terraform.Options for testName exactly like
+// DefaultOptions, except the temp module dir and generated name_prefix are
+// persisted per testName (see testEnv) across separate `go test`
+// invocations - e.g. `go test -run TestFoo` with SKIP_setup=true after an
+// earlier SKIP_teardown=true run reuses the dir and prefix that was
+// actually applied, instead of generating a new one that `terraform.Destroy`
+// would run against empty state.
+//
+// Use this only for tests that go through helpers.RunStage and may skip
+// their teardown stage; call helpers.CleanupTestData from that teardown
+// stage once resources are destroyed, or the persisted test_env under
+// /tmp/imladris-<testName>/ outlives the run and later runs keep reusing
+// its module dir and prefix. CleanupTestData removes only that stage data,
+// not the module dir itself. Tests that always apply (or
+// plan) and clean up within a single invocation should use DefaultOptions
+// instead.
+func StagedOptions(t *testing.T, testName string) *terraform.Options {
+	t.Helper()
+
+	env := loadOrCreateTestEnv(t, testName)
+
+	return &terraform.Options{
+		TerraformDir: env.TempModuleDir,
+		VarFiles:     varFilesFor(env.TempModuleDir, testName),
+		Vars: map[string]interface{}{
+			"name_prefix": env.NamePrefix,
+		},
+	}
+}
+
+// DefaultOptions builds terraform.Options for testName that are safe to run
+// under t.Parallel() alongside every other test in the suite:
+//
+//   - the module is copied to a unique temp dir via
+//     test_structure.CopyTerraformFolderToTemp, so concurrent runs don't
+//     corrupt each other's .terraform state or lock files
+//   - name_prefix gets a random 6-char suffix, so concurrent runs don't
+//     collide on resource names such as security group names; vpc_cidr is
+//     not changed, so concurrent runs create VPCs with the same CIDR block
+//   - tests/variables/<testName>.tfvars is appended as a var file if it
+//     exists, on top of the suite-wide tests/integration/test.tfvars
+//
+// The temp dir is scoped to this test invocation and removed via t.Cleanup
+// once the test completes, so editing the module takes effect on the very
+// next run. When any SKIP_<stage> variable is set, CopyTerraformFolderToTemp
+// returns the original module folder instead of a copy; that folder is then
+// used in place and is not removed. Tests that go through the stage
+// lifecycle and may skip their teardown should use StagedOptions instead.
+//
+// Callers can still set Targets, additional Vars, etc. on the returned
+// *terraform.Options before using it.
+func DefaultOptions(t *testing.T, testName string) *terraform.Options {
+	t.Helper()
+
+	tempModuleDir := testStructure.CopyTerraformFolderToTemp(t, "../../", ".")
+	t.Cleanup(func() {
+		if testStructure.SkipStageEnvVarSet() {
+			return
+		}
+		if err := os.RemoveAll(tempModuleDir); err != nil {
+			t.Logf("warning: failed to clean up temp module dir %s: %v", tempModuleDir, err)
+		}
+	})
+
+	namePrefix := fmt.Sprintf("devbox-%s-%s", testName, randomSuffix(t, 6))
+
+	return &terraform.Options{
+		TerraformDir: tempModuleDir,
+		VarFiles:     varFilesFor(tempModuleDir, testName),
+		Vars: map[string]interface{}{
+			"name_prefix": namePrefix,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+)
+
+// devboxInstanceType is the instance type the devbox module provisions.
+// Regions that don't offer it, or that have no VPC/EIP quota left, are
+// reported as unavailable by RegionHasCapacity.
+const devboxInstanceType = "t3.medium"
+
+const (
+	vpcQuotaCode = "L-F678F1CE" // VPCs per region
+	eipQuotaCode = "L-0263D0A3" // EC2-VPC Elastic IPs
+)
+
+// RegionHasCapacity reports whether region offers devboxInstanceType and has
+// headroom on the VPC and EIP quotas this module consumes one of each of.
+//
+// Callers (e.g. TestMatrix) should skip a region this returns false for
+// rather than attempt to apply into it - this is what prevents flaky
+// failures when a shared test account hits a per-region quota.
+//
+// It answers yes/no for one given region; this package has no helper that
+// elects a single region out of a preference list.
+func RegionHasCapacity(t *testing.T, region string) bool {
+	t.Helper()
+
+	baseCfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		t.Fatalf("loading AWS SDK config: %v", err)
+	}
+
+	cfg := baseCfg.Copy()
+	cfg.Region = region
+
+	ec2Client := ec2.NewFromConfig(cfg)
+	ctx := context.Background()
+
+	offerings, err := ec2Client.DescribeInstanceTypeOfferings(ctx, &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: types.LocationTypeRegion,
+		Filters: []types.Filter{
+			{Name: aws.String("instance-type"), Values: []string{devboxInstanceType}},
+		},
+	})
+	if err != nil {
+		t.Logf("region %s: describing instance type offerings: %v", region, err)
+		return false
+	}
+	if len(offerings.InstanceTypeOfferings) == 0 {
+		t.Logf("region %s does not offer instance type %s", region, devboxInstanceType)
+		return false
+	}
+
+	vpcs, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{})
+	if err != nil {
+		t.Logf("region %s: describing VPCs: %v", region, err)
+		return false
+	}
+
+	addresses, err := ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		t.Logf("region %s: describing Elastic IPs: %v", region, err)
+		return false
+	}
+
+	quotasClient := servicequotas.NewFromConfig(cfg)
+	if !quotaHasHeadroom(t, quotasClient, region, "vpc", vpcQuotaCode, len(vpcs.Vpcs)) {
+		return false
+	}
+	if !quotaHasHeadroom(t, quotasClient, region, "ec2", eipQuotaCode, len(addresses.Addresses)) {
+		return false
+	}
+
+	return true
+}
+
+// quotaHasHeadroom reports whether used is strictly below the account's
+// quota for quotaCode, leaving room for the resource this test is about to
+// create.
+func quotaHasHeadroom(t *testing.T, client *servicequotas.Client, region, serviceCode, quotaCode string, used int) bool {
+	t.Helper()
+
+	out, err := client.GetServiceQuota(context.Background(), &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	if err != nil {
+		t.Logf("region %s: getting quota %s/%s: %v", region, serviceCode, quotaCode, err)
+		return false
+	}
+
+	limit := aws.ToFloat64(out.Quota.Value)
+	if float64(used) >= limit {
+		t.Logf("region %s: quota %s/%s has no headroom (%d used of %.0f)", region, serviceCode, quotaCode, used, limit)
+		return false
+	}
+
+	return true
+}
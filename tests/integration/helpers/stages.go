@@ -0,0 +1,85 @@
+// Package helpers provides shared test infrastructure for the integration
+// suite: a stage-based lifecycle for expensive AWS tests, workspace
+// isolation, cost guardrails, and region selection.
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// RunStage runs fn under the given stage name unless SKIP_<stageName> is set
+// to "true" in the environment, in which case the stage is skipped with a
+// log message. This lets a developer run `setup` once against real AWS and
+// then iterate on `verify` logic for seconds at a time via:
+//
+//	SKIP_setup=true SKIP_teardown=true go test -run TestFoo -v
+func RunStage(t *testing.T, stageName string, fn func()) {
+	t.Helper()
+
+	envVar := fmt.Sprintf("SKIP_%s", stageName)
+	if os.Getenv(envVar) == "true" {
+		t.Logf("Skipping stage %q because %s=true", stageName, envVar)
+		return
+	}
+
+	t.Logf("Running stage %q", stageName)
+	fn()
+}
+
+// dataDir returns the directory used to persist data between stages of a
+// given test, e.g. /tmp/imladris-TestVpcCreation/.
+func dataDir(testName string) string {
+	return filepath.Join(os.TempDir(), "imladris-"+testName)
+}
+
+// SaveTestData JSON-encodes value and writes it to <dataDir>/<key>.json so a
+// later stage (possibly a later `go test` invocation) can load it back.
+func SaveTestData(t *testing.T, testName, key string, value interface{}) {
+	t.Helper()
+
+	dir := dataDir(testName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("creating stage data dir %s: %v", dir, err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshaling stage data for %s: %v", key, err)
+	}
+
+	path := filepath.Join(dir, key+".json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("writing stage data to %s: %v", path, err)
+	}
+}
+
+// LoadTestData JSON-decodes <dataDir>/<key>.json into out. It fails the test
+// if the data is missing, since a verify/teardown stage that runs without
+// its setup output is a broken test, not a skippable one.
+func LoadTestData(t *testing.T, testName, key string, out interface{}) {
+	t.Helper()
+
+	path := filepath.Join(dataDir(testName), key+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading stage data from %s (did the setup stage run?): %v", path, err)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("unmarshaling stage data from %s: %v", path, err)
+	}
+}
+
+// CleanupTestData removes the stage data directory for a test. Call this
+// from the teardown stage once resources have been destroyed.
+func CleanupTestData(t *testing.T, testName string) {
+	t.Helper()
+
+	if err := os.RemoveAll(dataDir(testName)); err != nil {
+		t.Logf("warning: failed to clean up stage data for %s: %v", testName, err)
+	}
+}
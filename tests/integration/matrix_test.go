@@ -0,0 +1,49 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sethdf/imladris/tests/integration/helpers"
+)
+
+// candidateRegions is the set of regions TestMatrix fans out across. These
+// were picked for AZ/AMI diversity, not because the devbox is expected to
+// run in all three simultaneously.
+var candidateRegions = []string{"us-east-1", "us-west-2", "eu-west-1"}
+
+// TestMatrix applies the networking layer in each of candidateRegions as a
+// subtest, to catch the module accidentally hardcoding an AZ suffix that
+// only happens to exist in one region. Only the VPC, subnet and internet
+// gateway are targeted, so the instance and its AMI ID are not applied here.
+func TestMatrix(t *testing.T) {
+	t.Parallel()
+
+	for _, region := range candidateRegions {
+		region := region
+		t.Run(region, func(t *testing.T) {
+			t.Parallel()
+
+			if !helpers.RegionHasCapacity(t, region) {
+				t.Skipf("region %s lacks capacity/quota headroom for the devbox, skipping", region)
+			}
+
+			testName := "TestMatrix_" + region
+			terraformOptions := helpers.DefaultOptions(t, testName)
+			terraformOptions.Vars["region"] = region
+			terraformOptions.Targets = []string{
+				"aws_vpc.devbox",
+				"aws_subnet.devbox",
+				"aws_internet_gateway.devbox",
+			}
+
+			defer terraform.Destroy(t, terraformOptions)
+			terraform.InitAndApply(t, terraformOptions)
+
+			vpcCidr := terraform.Output(t, terraformOptions, "vpc_cidr")
+			assert.Equal(t, "10.0.0.0/16", vpcCidr)
+		})
+	}
+}
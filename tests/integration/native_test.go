@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"bufio"
+	"encoding/json"
+	"os/exec"
+	"testing"
+)
+
+// terraformTestMessage is the subset of `terraform test -json` output we
+// care about: one "test_run" message per run block, carrying its status.
+type terraformTestMessage struct {
+	Type string `json:"type"`
+	Run  struct {
+		Run    string `json:"run"`
+		Status string `json:"status"`
+	} `json:"test_run"`
+	Diagnostic struct {
+		Summary string `json:"summary"`
+		Detail  string `json:"detail"`
+	} `json:"diagnostic"`
+}
+
+// expectedRunStatus is the status a run block is expected to report, for
+// runs where that isn't the usual "pass". A missing required variable is a
+// configuration error terraform reports as "error", not a condition
+// failure expect_failures can reconcile into "pass" - see
+// tests/native/validation.tftest.hcl.
+var expectedRunStatus = map[string]string{
+	"rejects_missing_required_var": "error",
+}
+
+// TestTerraformNative shells out to `terraform test` against the native
+// .tftest.hcl suite under tests/native/ and reports each run block as its
+// own Go subtest, so failures show up with proper attribution instead of
+// a single opaque pass/fail for the whole suite.
+//
+// This is the fast, no-AWS-credentials-required pre-commit gate: validation
+// cases run with `command = plan`, structural cases run with
+// `mock_provider "aws"`. It complements, rather than replaces, the Terratest
+// integration suite in this package.
+func TestTerraformNative(t *testing.T) {
+	cmd := exec.Command("terraform", "test", "-json", "-test-directory=tests/native")
+	cmd.Dir = "../../"
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("creating stdout pipe: %v", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting terraform test: %v", err)
+	}
+
+	runs := map[string]string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var msg terraformTestMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			// terraform emits some non-JSON-message lines (e.g. plain log
+			// output); ignore lines that don't match our schema.
+			continue
+		}
+
+		if msg.Type == "test_run" && msg.Run.Run != "" {
+			runs[msg.Run.Run] = msg.Run.Status
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("reading terraform test output: %v", err)
+	}
+
+	// Wait after fully draining stdout; a failing `terraform test` exits
+	// non-zero even when every run block we care about passed, so we assert
+	// on parsed run status rather than the process exit code.
+	_ = cmd.Wait()
+
+	if len(runs) == 0 {
+		t.Fatal("terraform test produced no run results; is terraform >= 1.6 installed?")
+	}
+
+	for name, status := range runs {
+		t.Run(name, func(t *testing.T) {
+			want := "pass"
+			if s, ok := expectedRunStatus[name]; ok {
+				want = s
+			}
+			if status != want {
+				t.Errorf("run block %q reported status %q, want %q", name, status, want)
+			}
+		})
+	}
+}